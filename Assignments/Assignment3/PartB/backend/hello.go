@@ -1,7 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/cors"
@@ -12,79 +16,59 @@ import (
 	"realworld-backend/users"
 )
 
-func Migrate(db *gorm.DB) {
-	users.AutoMigrate()
-	db.AutoMigrate(&articles.ArticleModel{})
-	db.AutoMigrate(&articles.TagModel{})
-	db.AutoMigrate(&articles.FavoriteModel{})
-	db.AutoMigrate(&articles.ArticleUserModel{})
-	db.AutoMigrate(&articles.CommentModel{})
-}
-
-// Create performance indexes based on actual database schema
-func CreatePerformanceIndexes(db *gorm.DB) {
-	fmt.Println("Creating performance indexes...")
-	
-	// Article performance indexes
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_articles_created_at ON article_models(created_at)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_articles_author_id ON article_models(author_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_articles_slug ON article_models(slug)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_articles_deleted_at ON article_models(deleted_at)")
-	
-	// User performance indexes
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_email ON user_models(email)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_username ON user_models(username)")
-	
-	// Comments performance indexes (actual columns: article_id, author_id)
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_comments_article_id ON comment_models(article_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comment_models(author_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comment_models(deleted_at)")
-	
-	// Favorites performance indexes (actual columns: favorite_id, favorite_by_id)
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_favorites_favorite_id ON favorite_models(favorite_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_favorites_favorite_by_id ON favorite_models(favorite_by_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_favorites_deleted_at ON favorite_models(deleted_at)")
-	
-	// Follow performance indexes (actual columns: following_id, followed_by_id)
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_follows_following_id ON follow_models(following_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_follows_followed_by_id ON follow_models(followed_by_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_follows_deleted_at ON follow_models(deleted_at)")
-	
-	// ArticleUser performance indexes
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_article_users_user_id ON article_user_models(user_model_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_article_users_deleted_at ON article_user_models(deleted_at)")
-	
-	// Tag indexes
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_tags_tag ON tag_models(tag)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tag_models(deleted_at)")
-	
-	// Article-Tag junction table indexes
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_article_tags_article_id ON article_tags(article_model_id)")
-	db.Exec("CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_model_id)")
-	
-	fmt.Println("✅ Performance indexes created successfully!")
-	fmt.Println("📊 Indexes improve query performance for:")
-	fmt.Println("   - Article listing and filtering")
-	fmt.Println("   - User lookups and authentication")
-	fmt.Println("   - Comments on articles")
-	fmt.Println("   - Favorite/Unfavorite operations")
-	fmt.Println("   - User follow/unfollow")
-	fmt.Println("   - Tag filtering")
-	fmt.Println("   - Soft delete queries (deleted_at)")
+// migrateCommand runs "migrate up|down [steps]|status" against db and returns the first error
+func migrateCommand(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [steps]|status")
+	}
+	switch args[0] {
+	case "up":
+		count, err := MigrateUp(db)
+		if err != nil {
+			return fmt.Errorf("applied %d migration(s) before failure: %v", count, err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", count)
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+			steps = n
+		}
+		count, err := MigrateDown(db, steps)
+		if err != nil {
+			return fmt.Errorf("rolled back %d migration(s) before failure: %v", count, err)
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", count)
+		return nil
+	case "status":
+		return MigrateStatus(db, os.Stdout)
+	}
+	return fmt.Errorf("unknown migrate command %q", args[0])
 }
 
 func main() {
 
 	db := common.Init()
-	
-	// Run migrations first
-	Migrate(db)
-	
-	// Create performance indexes after migration
-	CreatePerformanceIndexes(db)
-	
 	defer db.Close()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrateCommand(db, os.Args[2:]); err != nil {
+			db.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Bring the schema up to date before serving
+	if _, err := MigrateUp(db); err != nil {
+		db.Close()
+		log.Fatal(err)
+	}
+
 	r := gin.Default()
 
 	// Configure CORS
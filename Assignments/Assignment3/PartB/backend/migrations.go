@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Migration is one numbered, reversible schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration in the schema_migrations table.
+type SchemaMigration struct {
+	Version   int `gorm:"primary_key;auto_increment:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Index definitions based on actual database schema
+var performanceIndexes = []struct {
+	name    string
+	table   string
+	columns string
+}{
+	// Article performance indexes
+	{"idx_articles_created_at", "article_models", "created_at"},
+	{"idx_articles_author_id", "article_models", "author_id"},
+	{"idx_articles_slug", "article_models", "slug"},
+	{"idx_articles_deleted_at", "article_models", "deleted_at"},
+
+	// User performance indexes
+	{"idx_users_email", "user_models", "email"},
+	{"idx_users_username", "user_models", "username"},
+
+	// Comments performance indexes (actual columns: article_id, author_id)
+	{"idx_comments_article_id", "comment_models", "article_id"},
+	{"idx_comments_author_id", "comment_models", "author_id"},
+	{"idx_comments_deleted_at", "comment_models", "deleted_at"},
+
+	// Favorites performance indexes (actual columns: favorite_id, favorite_by_id)
+	{"idx_favorites_favorite_id", "favorite_models", "favorite_id"},
+	{"idx_favorites_favorite_by_id", "favorite_models", "favorite_by_id"},
+	{"idx_favorites_deleted_at", "favorite_models", "deleted_at"},
+
+	// Follow performance indexes (actual columns: following_id, followed_by_id)
+	{"idx_follows_following_id", "follow_models", "following_id"},
+	{"idx_follows_followed_by_id", "follow_models", "followed_by_id"},
+	{"idx_follows_deleted_at", "follow_models", "deleted_at"},
+
+	// ArticleUser performance indexes
+	{"idx_article_users_user_id", "article_user_models", "user_model_id"},
+	{"idx_article_users_deleted_at", "article_user_models", "deleted_at"},
+
+	// Tag indexes
+	{"idx_tags_tag", "tag_models", "tag"},
+	{"idx_tags_deleted_at", "tag_models", "deleted_at"},
+
+	// Article-Tag junction table indexes
+	{"idx_article_tags_article_id", "article_tags", "article_model_id"},
+	{"idx_article_tags_tag_id", "article_tags", "tag_model_id"},
+}
+
+// execAll runs each statement in order and stops at the first error.
+func execAll(tx *gorm.DB, statements ...string) error {
+	for _, sql := range statements {
+		if err := tx.Exec(sql).Error; err != nil {
+			return fmt.Errorf("%q: %v", sql, err)
+		}
+	}
+	return nil
+}
+
+// Migrations lists every schema change in version order. Append new entries
+// with the next version number; never edit one that has been released.
+//
+// Migrations 1 and 2 freeze the schema that gorm's AutoMigrate produced for
+// the users and articles models, as explicit DDL with IF NOT EXISTS, so they
+// also apply cleanly to databases created before schema_migrations existed.
+// The DDL is written for SQLite, the dialect common.Init opens.
+// Later model changes need their own migration rather than a struct edit.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_user_tables",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE IF NOT EXISTS "user_models" ("id" integer primary key autoincrement,"username" varchar(255),"email" varchar(255),"bio" varchar(1024),"image" varchar(255),"password" varchar(255) NOT NULL)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS uix_user_models_email ON "user_models"("email")`,
+				`CREATE TABLE IF NOT EXISTS "follow_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"following_id" integer,"followed_by_id" integer)`,
+				`CREATE INDEX IF NOT EXISTS idx_follow_models_deleted_at ON "follow_models"("deleted_at")`,
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`DROP TABLE IF EXISTS "follow_models"`,
+				`DROP TABLE IF EXISTS "user_models"`,
+			)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_article_tables",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE IF NOT EXISTS "article_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"slug" varchar(255),"title" varchar(255),"description" varchar(2048),"body" varchar(2048),"author_id" integer)`,
+				`CREATE INDEX IF NOT EXISTS idx_article_models_deleted_at ON "article_models"("deleted_at")`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS uix_article_models_slug ON "article_models"("slug")`,
+				`CREATE TABLE IF NOT EXISTS "article_tags" ("article_model_id" integer,"tag_model_id" integer, PRIMARY KEY ("article_model_id","tag_model_id"))`,
+				`CREATE TABLE IF NOT EXISTS "tag_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"tag" varchar(255))`,
+				`CREATE INDEX IF NOT EXISTS idx_tag_models_deleted_at ON "tag_models"("deleted_at")`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS uix_tag_models_tag ON "tag_models"("tag")`,
+				`CREATE TABLE IF NOT EXISTS "favorite_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"favorite_id" integer,"favorite_by_id" integer)`,
+				`CREATE INDEX IF NOT EXISTS idx_favorite_models_deleted_at ON "favorite_models"("deleted_at")`,
+				`CREATE TABLE IF NOT EXISTS "article_user_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"user_model_id" integer)`,
+				`CREATE INDEX IF NOT EXISTS idx_article_user_models_deleted_at ON "article_user_models"("deleted_at")`,
+				`CREATE TABLE IF NOT EXISTS "comment_models" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"deleted_at" datetime,"article_id" integer,"author_id" integer,"body" varchar(2048))`,
+				`CREATE INDEX IF NOT EXISTS idx_comment_models_deleted_at ON "comment_models"("deleted_at")`,
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`DROP TABLE IF EXISTS "comment_models"`,
+				`DROP TABLE IF EXISTS "article_user_models"`,
+				`DROP TABLE IF EXISTS "favorite_models"`,
+				`DROP TABLE IF EXISTS "tag_models"`,
+				`DROP TABLE IF EXISTS "article_tags"`,
+				`DROP TABLE IF EXISTS "article_models"`,
+			)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_performance_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, idx := range performanceIndexes {
+				sql := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(%s)", idx.name, idx.table, idx.columns)
+				if err := tx.Exec(sql).Error; err != nil {
+					return fmt.Errorf("%s: %v", idx.name, err)
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(performanceIndexes) - 1; i >= 0; i-- {
+				idx := performanceIndexes[i]
+				if err := tx.Exec("DROP INDEX IF EXISTS " + idx.name).Error; err != nil {
+					return fmt.Errorf("%s: %v", idx.name, err)
+				}
+			}
+			return nil
+		},
+	},
+}
+
+func sortedMigrations() []Migration {
+	sorted := make([]Migration, len(Migrations))
+	copy(sorted, Migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// appliedVersions makes sure schema_migrations exists and returns the applied versions.
+func appliedVersions(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}).Error; err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// runMigration executes one step inside a transaction together with its
+// schema_migrations bookkeeping, so a failed statement leaves no trace.
+func runMigration(db *gorm.DB, m Migration, up bool) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	var err error
+	if up {
+		if err = m.Up(tx); err == nil {
+			err = tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+	} else {
+		if err = m.Down(tx); err == nil {
+			err = tx.Where("version = ?", m.Version).Delete(&SchemaMigration{}).Error
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d (%s): %v", m.Version, m.Name, err)
+	}
+	return tx.Commit().Error
+}
+
+// MigrateUp applies every pending migration in version order and stops at the first error.
+func MigrateUp(db *gorm.DB) (int, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, m := range sortedMigrations() {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := runMigration(db, m, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown rolls back the most recently applied `steps` migrations.
+func MigrateDown(db *gorm.DB, steps int) (int, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+	sorted := sortedMigrations()
+	count := 0
+	for i := len(sorted) - 1; i >= 0 && count < steps; i-- {
+		m := sorted[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := runMigration(db, m, false); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateStatus writes one line per known migration with its applied state.
+func MigrateStatus(db *gorm.DB, w io.Writer) error {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range sortedMigrations() {
+		state := "pending"
+		if row, ok := applied[m.Version]; ok {
+			state = "applied " + row.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d_%s\t%s\n", m.Version, m.Name, state)
+	}
+	return nil
+}
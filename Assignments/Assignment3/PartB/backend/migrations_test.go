@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"realworld-backend/common"
+)
+
+func TestMigrateUpDownStatus(t *testing.T) {
+	asserts := assert.New(t)
+
+	db := common.TestDBInit()
+	defer common.TestDBFree(db)
+
+	count, err := MigrateUp(db)
+	asserts.NoError(err, "MigrateUp should apply all migrations")
+	asserts.Equal(len(Migrations), count, "every migration should be applied once")
+	asserts.True(db.HasTable("article_models"), "article_models should exist after up")
+
+	var indexes int
+	asserts.NoError(db.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND name = ?",
+		"idx_articles_created_at").Row().Scan(&indexes))
+	asserts.Equal(1, indexes, "migration 3 should create idx_articles_created_at")
+
+	count, err = MigrateUp(db)
+	asserts.NoError(err)
+	asserts.Equal(0, count, "MigrateUp should be a no-op when nothing is pending")
+
+	var out bytes.Buffer
+	asserts.NoError(MigrateStatus(db, &out))
+	asserts.Contains(out.String(), "0003_create_performance_indexes\tapplied")
+
+	count, err = MigrateDown(db, 2)
+	asserts.NoError(err, "MigrateDown should roll back the latest migrations")
+	asserts.Equal(2, count)
+	asserts.False(db.HasTable("article_models"), "article_models should be dropped after down")
+	asserts.True(db.HasTable("user_models"), "user_models should survive a two-step down")
+
+	out.Reset()
+	asserts.NoError(MigrateStatus(db, &out))
+	asserts.Contains(out.String(), "0002_create_article_tables\tpending")
+}
+
+func TestMigrateUpStopsAndRollsBackOnError(t *testing.T) {
+	asserts := assert.New(t)
+
+	db := common.TestDBInit()
+	defer common.TestDBFree(db)
+
+	released := Migrations
+	defer func() { Migrations = released }()
+	Migrations = append(append([]Migration{}, released...), Migration{
+		Version: 999,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE "broken_probes" ("id" integer)`,
+				`THIS IS NOT SQL`,
+			)
+		},
+		Down: func(tx *gorm.DB) error { return nil },
+	})
+
+	count, err := MigrateUp(db)
+	asserts.Error(err, "MigrateUp should fail on an invalid statement")
+	asserts.Equal(len(released), count, "migrations before the broken one should be reported as applied")
+
+	var rows int
+	asserts.NoError(db.Model(&SchemaMigration{}).Where("version = ?", 999).Count(&rows).Error)
+	asserts.Equal(0, rows, "failed migration should not be recorded")
+	asserts.False(db.HasTable("broken_probes"), "tables created by the failed migration should be rolled back")
+}
+
+func TestMigrateCommandRejectsUnknown(t *testing.T) {
+	asserts := assert.New(t)
+
+	db := common.TestDBInit()
+	defer common.TestDBFree(db)
+
+	asserts.Error(migrateCommand(db, nil), "missing subcommand should fail")
+	asserts.Error(migrateCommand(db, []string{"sideways"}), "unknown subcommand should fail")
+	asserts.Error(migrateCommand(db, []string{"down", "zero"}), "non-numeric steps should fail")
+}